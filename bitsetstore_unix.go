@@ -0,0 +1,338 @@
+//go:build unix
+
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// --- Persistent Prime Database (mmap-backed BitsetStore) ---
+//
+// This file is built only on unix (syscall.Mmap/Munmap have no equivalent
+// in the standard library on Windows); the rest of the program stays
+// pure-stdlib and portable.
+//
+// BitsetStore turns a sieve result into a file that can be reused across
+// runs instead of recomputed: the composite bitset is memory-mapped, so
+// IsPrime/PrimePi/NextPrime read straight out of the OS page cache with no
+// per-process copy. Bit j of the bitset corresponds to the odd number
+// 2j+1; only odd numbers are stored since every even number above 2 is
+// composite. The prime 2 itself is handled specially and never stored.
+
+const (
+	bitsetStoreMagic     = "PPDB"    // Persistent Prime DB
+	bitsetStoreHeaderLen = 4 + 8 + 4 // magic + maxNum (int64) + wheelMod (int32)
+	// bitsetStoreWheelMod is the wheelMod value written to the header by the
+	// current build. Only the odd-only layout (wheelMod == 0) is built today;
+	// the field exists so a future wheel-210-backed store can be told apart
+	// from an odd-only one without guessing from file size alone.
+	bitsetStoreWheelMod = 0
+	// primePiBlockBytes is the size, in bitset bytes, of one block in the
+	// popcount sidecar index used by PrimePi.
+	primePiBlockBytes = 4096
+)
+
+// BitsetStore is a memory-mapped, odd-only composite bitset for the primes
+// in [0, maxNum], persisted to disk so it can be reused across runs without
+// re-sieving.
+type BitsetStore struct {
+	file           *os.File
+	data           []byte // mmap of the whole file: header followed by the bitset
+	bitset         []byte // data[bitsetStoreHeaderLen:], one bit per odd number
+	maxNum         int
+	blockPopcounts []int64 // blockPopcounts[i] = composite-bit count in bitset[:(i+1)*primePiBlockBytes]
+}
+
+// oddBitsetByteLen returns how many bytes are needed to store one bit per
+// odd number in [0, maxNum].
+func oddBitsetByteLen(maxNum int) int {
+	maxJ := (maxNum - 1) / 2
+	return (maxJ + 1 + 7) / 8
+}
+
+// oddBitsetSegmentWorker marks composite odd numbers directly into the
+// shared bitset passed in, instead of returning a per-segment []byte the
+// way segmentedSieveWorker does. Consecutive SegmentTasks carve [0, maxNum]
+// into SegmentSizeInNumbers-number chunks, and since that size is a multiple
+// of 16, each chunk's odd numbers map to a whole, non-overlapping run of bytes in
+// bitset - so concurrent workers never write to the same byte.
+func oddBitsetSegmentWorker(tasks <-chan SegmentTask, basePrimes []int, bitset []byte, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for task := range tasks {
+		for _, p := range basePrimes {
+			if p == 2 {
+				continue // Even numbers are never stored.
+			}
+			if p*p > task.high {
+				break
+			}
+
+			startMultipleInP := ((task.low + p - 1) / p) * p
+			if startMultipleInP%2 == 0 {
+				startMultipleInP += p
+			}
+			actualStartMarking := startMultipleInP
+			if actualStartMarking < p*p {
+				actualStartMarking = p * p
+				if actualStartMarking%2 == 0 {
+					actualStartMarking += p
+				}
+			}
+
+			for multiple := actualStartMarking; multiple <= task.high; multiple += 2 * p {
+				if multiple < task.low {
+					continue
+				}
+				j := (multiple - 1) / 2
+				byteIndex := j / 8
+				bitOffset := uint(j % 8)
+				bitset[byteIndex] |= 1 << bitOffset
+			}
+		}
+	}
+}
+
+// buildOddBitset sieves [0, maxNum] and marks every composite odd number in
+// bitset, using numWorkers workers in parallel.
+func buildOddBitset(bitset []byte, maxNum int, numWorkers int) {
+	sqrtMaxNum := int(math.Sqrt(float64(maxNum)))
+	basePrimes := sieveOfEratosthenesSequentialBase(sqrtMaxNum)
+
+	tasks := make(chan SegmentTask, numWorkers)
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go oddBitsetSegmentWorker(tasks, basePrimes, bitset, &wg)
+	}
+
+	for low := 0; low <= maxNum; low += SegmentSizeInNumbers {
+		high := low + SegmentSizeInNumbers - 1
+		if high > maxNum {
+			high = maxNum
+		}
+		tasks <- SegmentTask{low: low, high: high}
+	}
+	close(tasks)
+	wg.Wait()
+}
+
+// readBitsetStoreHeader reads and validates the header of an existing store
+// file, returning the maxNum it was built for. ok is false if the file is
+// too short, the magic doesn't match, or the wheel mod doesn't match the
+// format this build knows how to read.
+func readBitsetStoreHeader(f *os.File) (maxNum int, ok bool) {
+	header := make([]byte, bitsetStoreHeaderLen)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return 0, false
+	}
+	if string(header[:4]) != bitsetStoreMagic {
+		return 0, false
+	}
+	storedMaxNum := int64(binary.LittleEndian.Uint64(header[4:12]))
+	storedWheelMod := int32(binary.LittleEndian.Uint32(header[12:16]))
+	if storedWheelMod != bitsetStoreWheelMod {
+		return 0, false
+	}
+	return int(storedMaxNum), true
+}
+
+// writeBitsetStoreHeader writes the magic, maxNum and wheel mod to the start
+// of f.
+func writeBitsetStoreHeader(f *os.File, maxNum int) error {
+	header := make([]byte, bitsetStoreHeaderLen)
+	copy(header[:4], bitsetStoreMagic)
+	binary.LittleEndian.PutUint64(header[4:12], uint64(maxNum))
+	binary.LittleEndian.PutUint32(header[12:16], uint32(bitsetStoreWheelMod))
+	_, err := f.WriteAt(header, 0)
+	return err
+}
+
+// OpenOrBuild opens path as a BitsetStore covering [0, maxNum]. If path
+// already holds a store built for the same maxNum (and wheel mod), it is
+// reused as-is; otherwise the file is (re)built from scratch with a fresh
+// parallel sieve using NumSieveWorkersGlobal workers.
+func OpenOrBuild(path string, maxNum int) (*BitsetStore, error) {
+	if maxNum < 2 {
+		return nil, fmt.Errorf("OpenOrBuild: maxNum must be >= 2, got %d", maxNum)
+	}
+
+	bitsetLen := oddBitsetByteLen(maxNum)
+	totalLen := bitsetStoreHeaderLen + bitsetLen
+
+	reuse := false
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err == nil {
+		if storedMaxNum, ok := readBitsetStoreHeader(f); ok && storedMaxNum == maxNum {
+			reuse = true
+		} else {
+			f.Close()
+			f = nil
+		}
+	}
+
+	if f == nil {
+		f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("OpenOrBuild: %w", err)
+		}
+		if err := f.Truncate(int64(totalLen)); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("OpenOrBuild: %w", err)
+		}
+		if err := writeBitsetStoreHeader(f, maxNum); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("OpenOrBuild: %w", err)
+		}
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, totalLen, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("OpenOrBuild: mmap failed: %w", err)
+	}
+
+	store := &BitsetStore{
+		file:   f,
+		data:   data,
+		bitset: data[bitsetStoreHeaderLen:],
+		maxNum: maxNum,
+	}
+
+	if !reuse {
+		numWorkers := NumSieveWorkersGlobal
+		if numWorkers <= 0 {
+			numWorkers = 1
+		}
+		buildOddBitset(store.bitset, maxNum, numWorkers)
+	}
+
+	store.buildPopcountIndex()
+	return store, nil
+}
+
+// buildPopcountIndex scans the bitset once to fill in blockPopcounts, the
+// sidecar index PrimePi uses to avoid rescanning from byte 0 on every call.
+func (bs *BitsetStore) buildPopcountIndex() {
+	numBlocks := (len(bs.bitset) + primePiBlockBytes - 1) / primePiBlockBytes
+	bs.blockPopcounts = make([]int64, numBlocks)
+
+	var running int64
+	for i := 0; i < numBlocks; i++ {
+		start := i * primePiBlockBytes
+		end := start + primePiBlockBytes
+		if end > len(bs.bitset) {
+			end = len(bs.bitset)
+		}
+		for _, b := range bs.bitset[start:end] {
+			running += int64(bits.OnesCount8(b))
+		}
+		bs.blockPopcounts[i] = running
+	}
+}
+
+// countCompositesUpTo returns the number of composite bits set in
+// bitset[0..j] inclusive, using the block index to skip straight to the
+// byte containing j instead of scanning from the start.
+func (bs *BitsetStore) countCompositesUpTo(j int) int64 {
+	byteIndex := j / 8
+	blockIndex := byteIndex / primePiBlockBytes
+
+	var count int64
+	if blockIndex > 0 {
+		count = bs.blockPopcounts[blockIndex-1]
+	}
+	for b := blockIndex * primePiBlockBytes; b < byteIndex; b++ {
+		count += int64(bits.OnesCount8(bs.bitset[b]))
+	}
+
+	mask := byte(1<<uint(j%8+1)) - 1
+	count += int64(bits.OnesCount8(bs.bitset[byteIndex] & mask))
+	return count
+}
+
+// IsPrime reports whether n is prime, in O(1) via a single bit lookup.
+func (bs *BitsetStore) IsPrime(n int) bool {
+	if n < 2 || n > bs.maxNum {
+		return false
+	}
+	if n == 2 {
+		return true
+	}
+	if n%2 == 0 {
+		return false
+	}
+	j := (n - 1) / 2
+	byteIndex := j / 8
+	bitOffset := uint(j % 8)
+	return bs.bitset[byteIndex]&(1<<bitOffset) == 0
+}
+
+// NextPrime returns the smallest prime strictly greater than n, or -1 if
+// there is none within the store's range. It walks forward one number at a
+// time, so its cost scales with the local prime gap rather than n.
+func (bs *BitsetStore) NextPrime(n int) int {
+	if n < 2 {
+		return 2
+	}
+	for c := n + 1; c <= bs.maxNum; c++ {
+		if bs.IsPrime(c) {
+			return c
+		}
+	}
+	return -1
+}
+
+// PrimePi returns the number of primes <= n (the prime-counting function),
+// using the blockPopcounts sidecar index so the cost is a handful of block
+// lookups plus at most primePiBlockBytes*8 individual bits, i.e. O(N/64)
+// amortized over the whole store rather than O(n) per call.
+func (bs *BitsetStore) PrimePi(n int) int64 {
+	if n < 2 {
+		return 0
+	}
+	if n > bs.maxNum {
+		n = bs.maxNum
+	}
+
+	m := n
+	if m%2 == 0 {
+		m--
+	}
+	jm := (m - 1) / 2
+	if jm == 0 {
+		return 1 // Only the prime 2 is <= n.
+	}
+
+	composites := bs.countCompositesUpTo(jm)
+	primesAmongOdds := int64(jm) - composites
+	return primesAmongOdds + 1 // +1 for the prime 2.
+}
+
+// AllPrimes decodes the full store back into an ascending []int, the same
+// shape findPrimesWithSegmentedSieve returns.
+func (bs *BitsetStore) AllPrimes() []int {
+	primes := make([]int, 0)
+	if bs.maxNum >= 2 {
+		primes = append(primes, 2)
+	}
+	for n := 3; n <= bs.maxNum; n += 2 {
+		if bs.IsPrime(n) {
+			primes = append(primes, n)
+		}
+	}
+	return primes
+}
+
+// Close unmaps the bitset and closes the underlying file.
+func (bs *BitsetStore) Close() error {
+	if err := syscall.Munmap(bs.data); err != nil {
+		return err
+	}
+	return bs.file.Close()
+}
@@ -0,0 +1,117 @@
+//go:build unix
+
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestBitsetStore_BuildAndReuse checks that OpenOrBuild builds a correct
+// store from scratch, and that reopening the same path reuses the file
+// instead of rebuilding it (verified by corrupting the on-disk bitset
+// between opens and checking the corruption survives the second open).
+func TestBitsetStore_BuildAndReuse(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	const maxNum = 10_000
+	dir := t.TempDir()
+	path := dir + "/primes.db"
+
+	store, err := OpenOrBuild(path, maxNum)
+	if err != nil {
+		t.Fatalf("OpenOrBuild() error = %v", err)
+	}
+
+	want := findPrimesWithSegmentedSieve(maxNum, 4)
+	got := store.AllPrimes()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("AllPrimes() produced %d primes; want %d", len(got), len(want))
+	}
+
+	for _, p := range []int{0, 1, 2, 3, 4, 9, 9973, maxNum} {
+		wantPrime := false
+		for _, w := range want {
+			if w == p {
+				wantPrime = true
+				break
+			}
+		}
+		if got := store.IsPrime(p); got != wantPrime {
+			t.Errorf("IsPrime(%d) = %v; want %v", p, got, wantPrime)
+		}
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Flip a bit that marks 9 (index (9-1)/2=4) composite, so reuse is
+	// detectable: if OpenOrBuild rebuilt instead of reusing, this bit would
+	// be restored to its correct (composite) state.
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("os.OpenFile() error = %v", err)
+	}
+	header := make([]byte, bitsetStoreHeaderLen)
+	b := make([]byte, 1)
+	if _, err := f.ReadAt(b, int64(len(header))); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	b[0] &^= 1 << 4 // Clear the bit for 9, making it look prime.
+	if _, err := f.WriteAt(b, int64(len(header))); err != nil {
+		t.Fatalf("WriteAt() error = %v", err)
+	}
+	f.Close()
+
+	reopened, err := OpenOrBuild(path, maxNum)
+	if err != nil {
+		t.Fatalf("OpenOrBuild() (reopen) error = %v", err)
+	}
+	defer reopened.Close()
+	if !reopened.IsPrime(9) {
+		t.Errorf("IsPrime(9) = false after reopen; want true (store was rebuilt instead of reused)")
+	}
+}
+
+// TestBitsetStore_NextPrimeAndPrimePi checks NextPrime and PrimePi against
+// the prime list produced by findPrimesWithSegmentedSieve.
+func TestBitsetStore_NextPrimeAndPrimePi(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	const maxNum = 20_000
+	dir := t.TempDir()
+	store, err := OpenOrBuild(dir+"/primes.db", maxNum)
+	if err != nil {
+		t.Fatalf("OpenOrBuild() error = %v", err)
+	}
+	defer store.Close()
+
+	primes := findPrimesWithSegmentedSieve(maxNum, 4)
+
+	for i, p := range primes {
+		want := int64(i + 1)
+		if got := store.PrimePi(p); got != want {
+			t.Errorf("PrimePi(%d) = %d; want %d", p, got, want)
+		}
+	}
+
+	for i := 0; i < len(primes)-1; i++ {
+		if got := store.NextPrime(primes[i]); got != primes[i+1] {
+			t.Errorf("NextPrime(%d) = %d; want %d", primes[i], got, primes[i+1])
+		}
+	}
+	if got := store.NextPrime(maxNum); got != -1 {
+		t.Errorf("NextPrime(%d) = %d; want -1 (no further prime in range)", maxNum, got)
+	}
+	if got := store.NextPrime(0); got != 2 {
+		t.Errorf("NextPrime(0) = %d; want 2", got)
+	}
+}
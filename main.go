@@ -16,6 +16,10 @@ const (
 	// A larger size reduces overhead but increases memory per worker and cache pressure.
 	// 524288 numbers = 64KB bitset per worker (524288 / 8 bytes)
 	SegmentSizeInNumbers = 524288
+	// WheelModulus is the product of the first four primes (2*3*5*7). Every
+	// integer coprime to WheelModulus is a candidate prime >7, so the wheel
+	// sieve only ever has to represent those residues.
+	WheelModulus = 210
 )
 
 // --- Bitset Helper Functions (for segment bitsets) ---
@@ -38,6 +42,188 @@ func isBitMarkedSegment(indexInSegment int, segmentBitset []byte) bool {
 	return (segmentBitset[byteIndex] & (1 << bitOffset)) != 0
 }
 
+// --- Wheel-210 Lookup Tables ---
+//
+// These are computed once at package init from WheelModulus and stay fixed
+// regardless of maxNum. They let the wheel sieve convert between "number
+// space" and "bitset index space" without ever computing a gcd at sieve time.
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// computeWheelResidues returns the residues in [1, WheelModulus) that are
+// coprime to WheelModulus, in ascending order. For WheelModulus=210 this is
+// the 48 residues coprime to 2*3*5*7.
+func computeWheelResidues() []int {
+	residues := make([]int, 0, 48)
+	for r := 1; r < WheelModulus; r++ {
+		if gcd(r, WheelModulus) == 1 {
+			residues = append(residues, r)
+		}
+	}
+	return residues
+}
+
+var wheelResidues = computeWheelResidues()
+
+// wheelResidueIndex maps a residue in [0, WheelModulus) to its index in
+// wheelResidues, or -1 if the residue is not coprime to WheelModulus.
+var wheelResidueIndex = computeWheelResidueIndex()
+
+func computeWheelResidueIndex() [WheelModulus]int {
+	var idx [WheelModulus]int
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i, r := range wheelResidues {
+		idx[r] = i
+	}
+	return idx
+}
+
+// wheelGaps holds the gap, in number-space, from wheelResidues[i] to the next
+// coprime residue (wrapping the last gap back to WheelModulus+wheelResidues[0]).
+// Multiplying wheelGaps[i] by a base prime p gives the step from one multiple
+// of p that's coprime to WheelModulus to the next, so marking never has to
+// revisit a residue that's already excluded by 2, 3, 5, or 7.
+var wheelGaps = computeWheelGaps()
+
+func computeWheelGaps() []int {
+	gaps := make([]int, len(wheelResidues))
+	for i := range wheelResidues {
+		next := wheelResidues[(i+1)%len(wheelResidues)]
+		if i == len(wheelResidues)-1 {
+			next += WheelModulus
+		}
+		gaps[i] = next - wheelResidues[i]
+	}
+	return gaps
+}
+
+// wheelPrefixCount[r] is the number of residues in wheelResidues strictly
+// less than r, for r in [0, WheelModulus]. It turns "how many wheel numbers
+// are below n" into an O(1) lookup instead of a scan.
+var wheelPrefixCount = computeWheelPrefixCount()
+
+func computeWheelPrefixCount() [WheelModulus + 1]int {
+	var prefix [WheelModulus + 1]int
+	ri := 0
+	for r := 0; r <= WheelModulus; r++ {
+		for ri < len(wheelResidues) && wheelResidues[ri] < r {
+			ri++
+		}
+		prefix[r] = ri
+	}
+	return prefix
+}
+
+// wheelUpperIndex[r] is the smallest index i such that wheelResidues[i] >= r,
+// or len(wheelResidues) if no such residue exists in the current cycle (in
+// which case the caller must roll over into the next cycle of WheelModulus).
+var wheelUpperIndex = computeWheelUpperIndex()
+
+func computeWheelUpperIndex() [WheelModulus + 1]int {
+	var upper [WheelModulus + 1]int
+	ri := len(wheelResidues)
+	for r := WheelModulus; r >= 0; r-- {
+		for ri > 0 && wheelResidues[ri-1] >= r {
+			ri--
+		}
+		upper[r] = ri
+	}
+	return upper
+}
+
+// wheelCoprimeCountBefore returns how many integers in [0, n) are coprime to
+// WheelModulus. The difference of two such counts gives the number of wheel
+// candidates in any range, which is exactly the bitset size/index needed.
+func wheelCoprimeCountBefore(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	cycles := n / WheelModulus
+	rem := n % WheelModulus
+	return cycles*len(wheelResidues) + wheelPrefixCount[rem]
+}
+
+// nextWheelNumberAtLeast returns the smallest integer >= t that is coprime to
+// WheelModulus.
+func nextWheelNumberAtLeast(t int) int {
+	if t < 1 {
+		t = 1
+	}
+	cycles := t / WheelModulus
+	rem := t % WheelModulus
+	idx := wheelUpperIndex[rem]
+	if idx == len(wheelResidues) {
+		cycles++
+		idx = 0
+	}
+	return cycles*WheelModulus + wheelResidues[idx]
+}
+
+// --- Pre-Sieve Pattern (3, 5, 7, 11, 13, 17, 19) ---
+//
+// Multiples of these seven primes are dense enough that marking them one
+// scattered write at a time, in every single segment, dominates the
+// segmented sieve's inner loop. Since the pattern of which integers they
+// rule out repeats with period PreSievePrimesProduct, it's computed once
+// here and then copied into each segment's bitset before segmentedSieveWorker
+// ever touches a base prime.
+
+// preSievePrimes are folded into preSieveBitset instead of being marked by
+// segmentedSieveWorker's ordinary base-prime loop.
+var preSievePrimes = []int{3, 5, 7, 11, 13, 17, 19}
+
+// PreSievePrimesProduct is the period of preSieveBitset: 3*5*7*11*13*17*19.
+const PreSievePrimesProduct = 3 * 5 * 7 * 11 * 13 * 17 * 19
+
+// preSieveBitset has one bit per residue in [0, PreSievePrimesProduct); the
+// bit is set iff that residue is divisible by one of preSievePrimes.
+var preSieveBitset = computePreSieveBitset()
+
+func computePreSieveBitset() []byte {
+	bitset := make([]byte, (PreSievePrimesProduct+7)/8)
+	for _, p := range preSievePrimes {
+		for k := 0; k < PreSievePrimesProduct; k += p {
+			bitset[k/8] |= 1 << uint(k%8)
+		}
+	}
+	return bitset
+}
+
+// isPreSievedPrime reports whether p is one of the primes already folded into
+// preSieveBitset, so segmentedSieveWorker's base-prime loop can skip it.
+func isPreSievedPrime(p int) bool {
+	switch p {
+	case 3, 5, 7, 11, 13, 17, 19:
+		return true
+	}
+	return false
+}
+
+// fillSegmentFromPreSieve initializes segmentBitset's first numCount bits
+// from preSieveBitset, starting at residue (low mod PreSievePrimesProduct).
+// SegmentSizeInNumbers is well under PreSievePrimesProduct, so the source
+// cycles around at most once per segment.
+func fillSegmentFromPreSieve(segmentBitset []byte, low int, numCount int) {
+	start := ((low % PreSievePrimesProduct) + PreSievePrimesProduct) % PreSievePrimesProduct
+	for i := 0; i < numCount; i++ {
+		srcBit := start + i
+		if srcBit >= PreSievePrimesProduct {
+			srcBit -= PreSievePrimesProduct
+		}
+		if preSieveBitset[srcBit/8]&(1<<uint(srcBit%8)) != 0 {
+			segmentBitset[i/8] |= 1 << uint(i%8)
+		}
+	}
+}
+
 // --- Sequential Sieve for Base Primes (Optimized) ---
 func sieveOfEratosthenesSequentialBase(maxNumInternal int) []int {
 	if maxNumInternal < 2 {
@@ -73,13 +259,13 @@ func sieveOfEratosthenesSequentialBase(maxNumInternal int) []int {
 // SegmentTask defines a piece of work for a worker.
 type SegmentTask struct {
 	low, high int // Range [low, high] to sieve
-	// id        int // Optional: for ordered collection if strictly needed before final sort
+	id        int // Dispatch order, so results can be placed without a final sort
 }
 
 // SegmentResult holds primes found in a segment.
 type SegmentResult struct {
 	primes []int
-	// id     int
+	id     int // Matches the SegmentTask.id that produced it
 }
 
 // segmentedSieveWorker processes segments sent via the tasks channel.
@@ -100,7 +286,11 @@ func segmentedSieveWorker(
 		// Create a bitset for the current segment [task.low, task.high].
 		// The bitset index `k` corresponds to the number `task.low + k`.
 		segmentBitsetLen := (segmentNumCount + 7) / 8
-		segmentBitset := make([]byte, segmentBitsetLen) // Initialized to all zeros (all potentially prime)
+		segmentBitset := make([]byte, segmentBitsetLen)
+		// Pre-mark every multiple of 3, 5, 7, 11, 13, 17, 19 up front from the
+		// precomputed pattern, instead of scattering those writes across the
+		// base-prime loop below.
+		fillSegmentFromPreSieve(segmentBitset, task.low, segmentNumCount)
 
 		for _, p := range basePrimes {
 			// Optimization: if p*p > task.high, then this p and subsequent larger primes
@@ -110,6 +300,10 @@ func segmentedSieveWorker(
 			if int64(p)*int64(p) > int64(task.high) {
 				break
 			}
+			if isPreSievedPrime(p) {
+				// Already accounted for by fillSegmentFromPreSieve above.
+				continue
+			}
 
 			// Calculate the first multiple of p that is >= task.low
 			// startMultipleInP = ceil(task.low / p) * p
@@ -138,11 +332,13 @@ func segmentedSieveWorker(
 			if currentNum < 2 { // Primes are >= 2
 				continue
 			}
-			if !isBitMarkedSegment(i, segmentBitset) {
+			// preSieveBitset marks every multiple of 3, 5, 7, 11, 13, 17, 19,
+			// including those primes themselves; special-case them back to prime.
+			if isPreSievedPrime(currentNum) || !isBitMarkedSegment(i, segmentBitset) {
 				segmentPrimes = append(segmentPrimes, currentNum)
 			}
 		}
-		results <- SegmentResult{primes: segmentPrimes}
+		results <- SegmentResult{primes: segmentPrimes, id: task.id}
 	}
 	// log.Printf("Segmented Worker %d: Exiting", workerID)
 }
@@ -188,6 +384,7 @@ func findPrimesWithSegmentedSieve(maxNum int, numWorkers int) []int {
 	go func() {
 		defer close(tasks) // Close tasks channel when all tasks are sent
 		currentLow := 0
+		segmentID := 0
 		for currentLow <= maxNum {
 			currentHigh := currentLow + SegmentSizeInNumbers - 1
 			if currentHigh > maxNum {
@@ -196,28 +393,30 @@ func findPrimesWithSegmentedSieve(maxNum int, numWorkers int) []int {
 			if currentLow > currentHigh { // Should not happen if loop condition is currentLow <= maxNum
 				break
 			}
-			tasks <- SegmentTask{low: currentLow, high: currentHigh}
+			tasks <- SegmentTask{low: currentLow, high: currentHigh, id: segmentID}
 			currentLow += SegmentSizeInNumbers
+			segmentID++
 		}
 		log.Printf("Segmented Sieve: All %d segment tasks dispatched in %s", numDispatchedSegments, time.Since(dispatchTime))
 	}()
 
-	// --- Phase 3: Collect and Combine Results ---
+	// --- Phase 3: Collect Results Directly Into Dispatch Order ---
 	collectionStartTime := time.Now()
 	var wgCollector sync.WaitGroup
 	wgCollector.Add(numDispatchedSegments) // Expect one result per dispatched segment
 
-	intermediateCollectedPrimes := make([][]int, 0, numDispatchedSegments)
+	// Segments are disjoint and dispatched in ascending order, so placing
+	// each result at intermediateCollectedPrimes[result.id] (rather than
+	// appending in completion order) means the concatenation below is
+	// already sorted — no sort.Ints over the final ~N/ln(N) primes needed.
+	intermediateCollectedPrimes := make([][]int, numDispatchedSegments)
 	collectedPrimesCount := 0
-	uniquePrimesFound := 0 // This will be len(finalPrimes) after sort & unique
 
 	// Goroutine to collect results and manage wgCollector
 	go func() {
 		for result := range results {
-			intermediateCollectedPrimes = append(intermediateCollectedPrimes, result.primes)
-			collectedPrimesCount += len(result.primes) // Sum of primes in all segments (before sort/unique)
-			// Simple progress, not tied to SieveProgressReportIntervalGlobal yet
-			// log.Printf("Segmented Sieve: Collected segment result (approx %d primes so far)", collectedPrimesCount)
+			intermediateCollectedPrimes[result.id] = result.primes
+			collectedPrimesCount += len(result.primes)
 			wgCollector.Done()
 		}
 	}()
@@ -228,48 +427,879 @@ func findPrimesWithSegmentedSieve(maxNum int, numWorkers int) []int {
 	log.Printf("Segmented Sieve: All segment results (%d segments) collected in %s. Raw primes collected: %d",
 		numDispatchedSegments, time.Since(collectionStartTime), collectedPrimesCount)
 
-	// --- Final Assembly and Sorting ---
+	// --- Final Assembly (already sorted; no sort.Ints required) ---
 	assemblyStartTime := time.Now()
-	// Estimate capacity for the final list of primes
-	finalCapacity := 0
-	if maxNum > 1 {
-		logMax := math.Log(float64(maxNum))
-		if logMax > 0 {
-			finalCapacity = int(float64(maxNum) / logMax) // Prime Number Theorem approximation
+	finalPrimes := make([]int, 0, collectedPrimesCount)
+	for _, segmentPrimes := range intermediateCollectedPrimes {
+		finalPrimes = append(finalPrimes, segmentPrimes...)
+	}
+
+	log.Printf("Segmented Sieve: Primes assembled in order in %s. Total unique primes found: %d",
+		time.Since(assemblyStartTime), len(finalPrimes))
+	log.Printf("Segmented Sieve: Total time for findPrimesWithSegmentedSieve: %s", time.Since(overallStartTime))
+
+	// Ensure workers are fully done (though they should be if tasks and results are closed)
+	wgWorkers.Wait()
+
+	// Progress reporting is a bit coarse here. Could be integrated into collection.
+	// For instance, print every X primes appended to finalPrimes during the append loop,
+	// but that might slow it down. The current logging provides phase timings.
+
+	return finalPrimes
+}
+
+// EmitPrimes sieves [0, maxNum] the same way findPrimesWithSegmentedSieve
+// does, but streams primes to ch as soon as the lowest-id still-pending
+// segment completes, instead of waiting for every segment and assembling a
+// slice. Segments can still finish out of dispatch order, so a small reorder
+// buffer holds the ones that arrived early until their turn comes up. ch is
+// closed once every segment has been streamed.
+func EmitPrimes(maxNum int, numWorkers int, ch chan<- int) {
+	defer close(ch)
+	if maxNum < 2 {
+		return
+	}
+
+	sqrtMaxNum := int(math.Sqrt(float64(maxNum)))
+	basePrimes := sieveOfEratosthenesSequentialBase(sqrtMaxNum)
+
+	tasks := make(chan SegmentTask, numWorkers)
+	results := make(chan SegmentResult, numWorkers)
+
+	var wgWorkers sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wgWorkers.Add(1)
+		go segmentedSieveWorker(w, tasks, results, basePrimes, &wgWorkers)
+	}
+
+	numSegments := 0
+	for low := 0; low <= maxNum; low += SegmentSizeInNumbers {
+		numSegments++
+	}
+
+	go func() {
+		defer close(tasks)
+		currentLow := 0
+		for segmentID := 0; segmentID < numSegments; segmentID++ {
+			currentHigh := currentLow + SegmentSizeInNumbers - 1
+			if currentHigh > maxNum {
+				currentHigh = maxNum
+			}
+			tasks <- SegmentTask{low: currentLow, high: currentHigh, id: segmentID}
+			currentLow += SegmentSizeInNumbers
+		}
+	}()
+
+	// Reorder buffer: segments that complete before their turn wait here.
+	pending := make(map[int][]int)
+	nextToEmit := 0
+	for received := 0; received < numSegments; received++ {
+		result := <-results
+		pending[result.id] = result.primes
+		for {
+			primes, ok := pending[nextToEmit]
+			if !ok {
+				break
+			}
+			for _, p := range primes {
+				ch <- p
+			}
+			delete(pending, nextToEmit)
+			nextToEmit++
 		}
 	}
-	if finalCapacity <= 0 { // Fallback for small maxNum or if PNT estimate is off
-		finalCapacity = collectedPrimesCount / 2 // A rough heuristic
-		if finalCapacity < 10 {
-			finalCapacity = 10
+	close(results)
+	wgWorkers.Wait()
+}
+
+// --- Wheel-210 Segmented Sieve ---
+
+// WheelSegmentTask defines a wheel-sieve piece of work for a worker. Unlike
+// SegmentTask, the bitset it implies only covers residues coprime to
+// WheelModulus, not every number.
+type WheelSegmentTask struct {
+	low, high int // Range [low, high] to sieve
+}
+
+// WheelSegmentResult holds primes >7 found in a wheel segment.
+type WheelSegmentResult struct {
+	primes []int
+}
+
+// wheelSegmentedSieveWorker processes wheel segments sent via the tasks
+// channel. It mirrors segmentedSieveWorker, but the bitset it allocates only
+// has one bit per residue coprime to WheelModulus instead of one bit per
+// number, and marking steps by wheelGaps[i]*p instead of p.
+func wheelSegmentedSieveWorker(
+	workerID int,
+	tasks <-chan WheelSegmentTask,
+	results chan<- WheelSegmentResult,
+	basePrimes []int, // Primes up to sqrt(maxNum), including 2, 3, 5, 7
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+
+	for task := range tasks {
+		// The bitset only needs one bit per wheel candidate in [task.low, task.high].
+		segmentBaseCount := wheelCoprimeCountBefore(task.low)
+		segmentCandidateCount := wheelCoprimeCountBefore(task.high+1) - segmentBaseCount
+		segmentBitsetLen := (segmentCandidateCount + 7) / 8
+		segmentBitset := make([]byte, segmentBitsetLen) // Initialized to all zeros (all potentially prime)
+
+		for _, p := range basePrimes {
+			if p <= 7 {
+				// 2, 3, and 5, 7 are baked into the wheel itself: no multiple of
+				// them is ever a candidate residue, so there's nothing to mark.
+				continue
+			}
+			if int64(p)*int64(p) > int64(task.high) {
+				break
+			}
+
+			// The first candidate multiple of p must itself be p*m for some m
+			// coprime to WheelModulus (see nextWheelNumberAtLeast), and must be
+			// >= max(low, p*p) just like the plain segmented sieve.
+			startTarget := task.low
+			if p*p > startTarget {
+				startTarget = p * p
+			}
+			m := nextWheelNumberAtLeast((startTarget + p - 1) / p)
+			gapIdx := wheelResidueIndex[m%WheelModulus]
+
+			for multiple := p * m; multiple <= task.high; {
+				if multiple >= task.low {
+					markBitSegment(wheelCoprimeCountBefore(multiple)-segmentBaseCount, segmentBitset)
+				}
+				multiple += p * wheelGaps[gapIdx]
+				gapIdx = (gapIdx + 1) % len(wheelGaps)
+			}
 		}
+
+		// Collect primes from this segment's bitset.
+		segmentPrimes := make([]int, 0, segmentCandidateCount/4) // Rough pre-allocation
+		for currentNum := task.low; currentNum <= task.high; currentNum++ {
+			if currentNum < 2 { // Primes are >= 2; 1 is coprime to the wheel but not prime.
+				continue
+			}
+			idx := wheelResidueIndex[currentNum%WheelModulus]
+			if idx < 0 {
+				continue // Not a wheel candidate: excluded by 2, 3, 5, or 7.
+			}
+			if !isBitMarkedSegment(wheelCoprimeCountBefore(currentNum)-segmentBaseCount, segmentBitset) {
+				segmentPrimes = append(segmentPrimes, currentNum)
+			}
+		}
+		results <- WheelSegmentResult{primes: segmentPrimes}
 	}
+}
+
+// findPrimesWithWheelSieve implements a parallel segmented Sieve of
+// Eratosthenes using a mod-210 wheel (skipping multiples of 2, 3, 5, 7) on
+// top of the same worker/task architecture as findPrimesWithSegmentedSieve.
+// Restricting every segment's bitset to residues coprime to 210 cuts both
+// the marking work and the memory per segment to about 48/210 (~23%) of the
+// one-bit-per-number footprint of findPrimesWithSegmentedSieve.
+func findPrimesWithWheelSieve(maxNum int, numWorkers int) []int {
+	if maxNum < 2 {
+		return []int{}
+	}
+	overallStartTime := time.Now()
 
-	finalPrimes := make([]int, 0, finalCapacity)
+	// --- Phase 1: Find base primes up to sqrt(maxNum) ---
+	sqrtMaxNum := int(math.Sqrt(float64(maxNum)))
+	basePrimesStartTime := time.Now()
+	log.Printf("Wheel Sieve: Finding base primes up to %d", sqrtMaxNum)
+	basePrimes := sieveOfEratosthenesSequentialBase(sqrtMaxNum)
+	log.Printf("Wheel Sieve: Found %d base primes in %s", len(basePrimes), time.Since(basePrimesStartTime))
+
+	tasks := make(chan WheelSegmentTask, numWorkers)
+	results := make(chan WheelSegmentResult, numWorkers)
+
+	// --- Start Worker Goroutines ---
+	var wgWorkers sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wgWorkers.Add(1)
+		go wheelSegmentedSieveWorker(w, tasks, results, basePrimes, &wgWorkers)
+	}
+
+	// --- Phase 2: Dispatch Segment Tasks ---
+	dispatchTime := time.Now()
+	numDispatchedSegments := 0
+	low := 0
+	for low <= maxNum {
+		numDispatchedSegments++
+		low += SegmentSizeInNumbers
+	}
+
+	go func() {
+		defer close(tasks)
+		currentLow := 0
+		for currentLow <= maxNum {
+			currentHigh := currentLow + SegmentSizeInNumbers - 1
+			if currentHigh > maxNum {
+				currentHigh = maxNum
+			}
+			tasks <- WheelSegmentTask{low: currentLow, high: currentHigh}
+			currentLow += SegmentSizeInNumbers
+		}
+		log.Printf("Wheel Sieve: All %d segment tasks dispatched in %s", numDispatchedSegments, time.Since(dispatchTime))
+	}()
+
+	// --- Phase 3: Collect and Combine Results ---
+	collectionStartTime := time.Now()
+	var wgCollector sync.WaitGroup
+	wgCollector.Add(numDispatchedSegments)
+
+	intermediateCollectedPrimes := make([][]int, 0, numDispatchedSegments)
+	collectedPrimesCount := 0
+
+	go func() {
+		for result := range results {
+			intermediateCollectedPrimes = append(intermediateCollectedPrimes, result.primes)
+			collectedPrimesCount += len(result.primes)
+			wgCollector.Done()
+		}
+	}()
+
+	wgCollector.Wait()
+	close(results)
+
+	log.Printf("Wheel Sieve: All segment results (%d segments) collected in %s. Raw primes collected: %d",
+		numDispatchedSegments, time.Since(collectionStartTime), collectedPrimesCount)
+
+	// --- Final Assembly: prepend 2, 3, 5, 7, append the segment primes, then sort ---
+	assemblyStartTime := time.Now()
+	finalPrimes := make([]int, 0, collectedPrimesCount+4)
+	for _, p := range []int{2, 3, 5, 7} {
+		if p <= maxNum {
+			finalPrimes = append(finalPrimes, p)
+		}
+	}
 	for _, segmentPrimes := range intermediateCollectedPrimes {
 		finalPrimes = append(finalPrimes, segmentPrimes...)
 	}
-
-	// Sort the combined list to ensure primes are in order.
-	// This step also implicitly handles uniqueness if primes were somehow redundantly generated
-	// by different segments (which they shouldn't be with disjoint segments).
 	sort.Ints(finalPrimes)
-	uniquePrimesFound = len(finalPrimes) // After sort, len gives unique prime count if no duplicates
 
-	log.Printf("Segmented Sieve: Primes combined and sorted in %s. Total unique primes found: %d",
-		time.Since(assemblyStartTime), uniquePrimesFound)
-	log.Printf("Segmented Sieve: Total time for findPrimesWithSegmentedSieve: %s", time.Since(overallStartTime))
+	log.Printf("Wheel Sieve: Primes combined and sorted in %s. Total unique primes found: %d",
+		time.Since(assemblyStartTime), len(finalPrimes))
+	log.Printf("Wheel Sieve: Total time for findPrimesWithWheelSieve: %s", time.Since(overallStartTime))
 
-	// Ensure workers are fully done (though they should be if tasks and results are closed)
 	wgWorkers.Wait()
 
-	// Progress reporting is a bit coarse here. Could be integrated into collection.
-	// For instance, print every X primes appended to finalPrimes during the append loop,
-	// but that might slow it down. The current logging provides phase timings.
+	return finalPrimes
+}
+
+// --- Streaming Prime Generator ---
 
+// initialStreamBaseBound is the starting bound for a PrimeStream's base
+// primes. It only has to cover sqrt(SegmentSizeInNumbers); PrimeStream grows
+// it on demand as the stream advances past Bound*Bound.
+const initialStreamBaseBound = 1024
+
+// PrimeStream yields primes in order without a precommitted maxNum. It
+// pipelines segments ahead of the consumer using per-worker task/result
+// channels backed by segmentedSieveWorker, and grows its base-prime list
+// whenever the next segment would need primes beyond sqrt of what it already
+// has. A PrimeStream is intended for single-goroutine use, mirroring the
+// usage pattern of an iterator.
+type PrimeStream struct {
+	numWorkers    int
+	pipelineDepth int
+
+	basePrimes []int
+	baseBound  int // basePrimes holds exactly the primes in [2, baseBound]
+
+	workerTasks   []chan SegmentTask
+	workerResults []chan SegmentResult
+	wgWorkers     sync.WaitGroup
+
+	dispatchIdx int // index of the next segment to dispatch
+	collectIdx  int // index of the next segment to collect
+
+	buffer []int
+	bufPos int
+	peeked *int // one value pushed back by Range, returned before refilling
+
+	// pendingSegments holds segments collected by ensureBaseCoverage while
+	// draining the old worker pool before a restart; refill consumes these
+	// (in order) before dispatching anything to the new pool.
+	pendingSegments [][]int
+}
+
+// NewPrimeStream creates a PrimeStream that pipelines up to pipelineDepth
+// segments ahead of the consumer across numWorkers workers.
+func NewPrimeStream(numWorkers, pipelineDepth int) *PrimeStream {
+	if numWorkers <= 0 {
+		numWorkers = 1
+	}
+	if pipelineDepth <= 0 {
+		pipelineDepth = 1
+	}
+	ps := &PrimeStream{
+		numWorkers:    numWorkers,
+		pipelineDepth: pipelineDepth,
+		basePrimes:    sieveOfEratosthenesSequentialBase(initialStreamBaseBound),
+		baseBound:     initialStreamBaseBound,
+	}
+	ps.startWorkers()
+	return ps
+}
+
+// startWorkers launches ps.numWorkers instances of segmentedSieveWorker, each
+// with its own buffered task/result channel pair so that segments assigned to
+// different workers can be in flight at the same time.
+func (ps *PrimeStream) startWorkers() {
+	ps.workerTasks = make([]chan SegmentTask, ps.numWorkers)
+	ps.workerResults = make([]chan SegmentResult, ps.numWorkers)
+	for w := 0; w < ps.numWorkers; w++ {
+		ps.workerTasks[w] = make(chan SegmentTask, ps.pipelineDepth)
+		ps.workerResults[w] = make(chan SegmentResult, ps.pipelineDepth)
+		ps.wgWorkers.Add(1)
+		go segmentedSieveWorker(w, ps.workerTasks[w], ps.workerResults[w], ps.basePrimes, &ps.wgWorkers)
+	}
+}
+
+// stopWorkers closes every worker's task channel and waits for it to exit.
+func (ps *PrimeStream) stopWorkers() {
+	for _, tasks := range ps.workerTasks {
+		close(tasks)
+	}
+	ps.wgWorkers.Wait()
+}
+
+// ensureBaseCoverage grows ps.basePrimes, restarting the worker pool with the
+// new list, until sqrt(segHigh) is covered. Base primes must cover
+// sqrt(current segment high) before that segment is dispatched.
+//
+// startWorkers replaces workerTasks/workerResults with brand-new channels,
+// so any segment already dispatched to the current pool but not yet
+// collected would otherwise be stranded in the old, now-unreferenced result
+// channel: refill would then block forever waiting for a segment the new
+// pool never received. So before tearing the pool down, every outstanding
+// segment is drained and stashed in pendingSegments, in collection order,
+// for refill to hand out before it asks the new pool for anything.
+func (ps *PrimeStream) ensureBaseCoverage(segHigh int) {
+	if segHigh <= ps.baseBound*ps.baseBound {
+		return
+	}
+	newBound := ps.baseBound
+	for newBound*newBound <= segHigh {
+		newBound *= 2
+	}
+
+	for ps.collectIdx < ps.dispatchIdx {
+		result := <-ps.workerResults[ps.collectIdx%ps.numWorkers]
+		ps.pendingSegments = append(ps.pendingSegments, result.primes)
+		ps.collectIdx++
+	}
+
+	ps.stopWorkers()
+	ps.basePrimes = sieveOfEratosthenesSequentialBase(newBound)
+	ps.baseBound = newBound
+	ps.startWorkers()
+}
+
+// dispatchSegment sends the idx-th segment ([idx*SegmentSizeInNumbers, ...])
+// to the worker that owns it (idx % numWorkers), extending base primes first
+// if needed.
+func (ps *PrimeStream) dispatchSegment(idx int) {
+	low := idx * SegmentSizeInNumbers
+	high := low + SegmentSizeInNumbers - 1
+	ps.ensureBaseCoverage(high)
+	ps.workerTasks[idx%ps.numWorkers] <- SegmentTask{low: low, high: high}
+}
+
+// fillPipeline dispatches segments until pipelineDepth of them are ahead of
+// the next one to collect.
+func (ps *PrimeStream) fillPipeline() {
+	for ps.dispatchIdx < ps.collectIdx+ps.pipelineDepth {
+		ps.dispatchSegment(ps.dispatchIdx)
+		ps.dispatchIdx++
+	}
+}
+
+// refill collects segments, in dispatch order, until one yields at least one
+// prime (sparse segments at large N can be entirely composite).
+func (ps *PrimeStream) refill() {
+	for ps.bufPos >= len(ps.buffer) {
+		if len(ps.pendingSegments) > 0 {
+			ps.buffer = ps.pendingSegments[0]
+			ps.pendingSegments = ps.pendingSegments[1:]
+			ps.bufPos = 0
+			continue
+		}
+		ps.fillPipeline()
+		if len(ps.pendingSegments) > 0 {
+			// fillPipeline's ensureBaseCoverage drained segments from the old
+			// pool into pendingSegments; hand those out before reading from
+			// the new pool, or they'd surface out of order.
+			continue
+		}
+		result := <-ps.workerResults[ps.collectIdx%ps.numWorkers]
+		ps.collectIdx++
+		ps.buffer = result.primes
+		ps.bufPos = 0
+	}
+}
+
+// Next returns the next prime in the stream. It always reports ok=true; the
+// bool return mirrors the conventional Go iterator shape so PrimeStream can
+// later be made finite without breaking callers.
+func (ps *PrimeStream) Next() (int, bool) {
+	if ps.peeked != nil {
+		v := *ps.peeked
+		ps.peeked = nil
+		return v, true
+	}
+	if ps.bufPos >= len(ps.buffer) {
+		ps.refill()
+	}
+	v := ps.buffer[ps.bufPos]
+	ps.bufPos++
+	return v, true
+}
+
+// Skip advances the stream past the next n primes.
+func (ps *PrimeStream) Skip(n int) {
+	for i := 0; i < n; i++ {
+		ps.Next()
+	}
+}
+
+// Range consumes the stream up through the first prime greater than hi,
+// returning the primes that fall in [lo, hi]. Primes below lo are consumed
+// and discarded; the first prime above hi is pushed back so a later call
+// continues from there instead of losing it.
+func (ps *PrimeStream) Range(lo, hi int) []int {
+	var out []int
+	for {
+		v, _ := ps.Next()
+		if v < lo {
+			continue
+		}
+		if v > hi {
+			ps.peeked = &v
+			break
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// Close stops the stream's worker pool. A PrimeStream must not be used after
+// Close.
+func (ps *PrimeStream) Close() {
+	ps.stopWorkers()
+}
+
+// --- Arbitrary-Range Sieve (64-bit) ---
+
+// isqrt64 returns floor(sqrt(n)) for n >= 0, correcting for float64 rounding
+// error so it stays exact near the top of the int64 range.
+func isqrt64(n int64) int64 {
+	if n < 0 {
+		return 0
+	}
+	r := int64(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for r+1 <= n/(r+1) { // (r+1)^2 <= n without overflowing int64
+		r++
+	}
+	return r
+}
+
+// RangeSegmentTask defines a 64-bit piece of work for a range-sieve worker.
+// Unlike SegmentTask, low and high need not start at (or be anywhere near) 0.
+type RangeSegmentTask struct {
+	low, high int64
+}
+
+// RangeSegmentResult holds primes found in a range segment.
+type RangeSegmentResult struct {
+	primes []int64
+}
+
+// RangeCountResult holds just the count of primes found in a range segment,
+// for callers that don't need the primes themselves.
+type RangeCountResult struct {
+	count int64
+}
+
+// sieveRangeSegmentBitset marks composites in [low, high] using basePrimes,
+// the same way segmentedSieveWorker does, but with all arithmetic on the
+// multiples done in int64 so low and high can sit anywhere below 2^63.
+func sieveRangeSegmentBitset(low, high int64, basePrimes []int) []byte {
+	segmentNumCount := high - low + 1
+	segmentBitsetLen := (segmentNumCount + 7) / 8
+	segmentBitset := make([]byte, segmentBitsetLen)
+
+	for _, p := range basePrimes {
+		p64 := int64(p)
+		if p64*p64 > high {
+			break
+		}
+
+		startMultipleInP := ((low + p64 - 1) / p64) * p64
+		actualStartMarking := startMultipleInP
+		if actualStartMarking < p64*p64 {
+			actualStartMarking = p64 * p64
+		}
+
+		for multiple := actualStartMarking; multiple <= high; multiple += p64 {
+			if multiple >= low {
+				markBitSegment(int(multiple-low), segmentBitset)
+			}
+		}
+	}
+	return segmentBitset
+}
+
+// rangeBasePrimes returns the base primes needed to sieve any segment whose
+// high end is at most high: everything up to sqrt(high).
+func rangeBasePrimes(high int64) []int {
+	return sieveOfEratosthenesSequentialBase(int(isqrt64(high)))
+}
+
+// planRangeSegments carves [low, high] into SegmentSizeInNumbers-sized tasks,
+// clamping low up to 0 and the last segment's high down to high.
+func planRangeSegments(low, high int64) []RangeSegmentTask {
+	if low < 0 {
+		low = 0
+	}
+	segSize := int64(SegmentSizeInNumbers)
+	tasks := make([]RangeSegmentTask, 0, (high-low)/segSize+1)
+	for cur := low; cur <= high; cur += segSize {
+		segHigh := cur + segSize - 1
+		if segHigh > high {
+			segHigh = high
+		}
+		tasks = append(tasks, RangeSegmentTask{low: cur, high: segHigh})
+	}
+	return tasks
+}
+
+// rangeSegmentWorker processes range segments, collecting the primes found
+// in each one.
+func rangeSegmentWorker(
+	workerID int,
+	tasks <-chan RangeSegmentTask,
+	results chan<- RangeSegmentResult,
+	basePrimes []int,
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+	for task := range tasks {
+		segmentBitset := sieveRangeSegmentBitset(task.low, task.high, basePrimes)
+		segmentNumCount := task.high - task.low + 1
+		segmentPrimes := make([]int64, 0, segmentNumCount/10+1)
+		for i := int64(0); i < segmentNumCount; i++ {
+			currentNum := task.low + i
+			if currentNum < 2 {
+				continue
+			}
+			if !isBitMarkedSegment(int(i), segmentBitset) {
+				segmentPrimes = append(segmentPrimes, currentNum)
+			}
+		}
+		results <- RangeSegmentResult{primes: segmentPrimes}
+	}
+}
+
+// rangeCountSegmentWorker processes range segments, counting primes found in
+// each one without ever allocating a primes slice.
+func rangeCountSegmentWorker(
+	workerID int,
+	tasks <-chan RangeSegmentTask,
+	results chan<- RangeCountResult,
+	basePrimes []int,
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+	for task := range tasks {
+		segmentBitset := sieveRangeSegmentBitset(task.low, task.high, basePrimes)
+		segmentNumCount := task.high - task.low + 1
+		var count int64
+		for i := int64(0); i < segmentNumCount; i++ {
+			if task.low+i < 2 {
+				continue
+			}
+			if !isBitMarkedSegment(int(i), segmentBitset) {
+				count++
+			}
+		}
+		results <- RangeCountResult{count: count}
+	}
+}
+
+// FindPrimesInRange returns, in ascending order, every prime in [low, high].
+// high can be arbitrarily large (e.g. 10^12 and beyond); only sqrt(high)
+// worth of base primes is ever computed, and the range is carved into
+// SegmentSizeInNumbers-sized segments dispatched to numWorkers workers, the
+// same way findPrimesWithSegmentedSieve dispatches from 0.
+func FindPrimesInRange(low, high int64, numWorkers int) []int64 {
+	if high < 2 || low > high {
+		return []int64{}
+	}
+	if numWorkers <= 0 {
+		numWorkers = 1
+	}
+
+	basePrimes := rangeBasePrimes(high)
+	segments := planRangeSegments(low, high)
+
+	tasks := make(chan RangeSegmentTask, numWorkers)
+	results := make(chan RangeSegmentResult, numWorkers)
+
+	var wgWorkers sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wgWorkers.Add(1)
+		go rangeSegmentWorker(w, tasks, results, basePrimes, &wgWorkers)
+	}
+
+	go func() {
+		defer close(tasks)
+		for _, t := range segments {
+			tasks <- t
+		}
+	}()
+
+	var wgCollector sync.WaitGroup
+	wgCollector.Add(len(segments))
+	collected := make([][]int64, 0, len(segments))
+	go func() {
+		for result := range results {
+			collected = append(collected, result.primes)
+			wgCollector.Done()
+		}
+	}()
+	wgCollector.Wait()
+	close(results)
+
+	finalPrimes := make([]int64, 0)
+	for _, segmentPrimes := range collected {
+		finalPrimes = append(finalPrimes, segmentPrimes...)
+	}
+	sort.Slice(finalPrimes, func(i, j int) bool { return finalPrimes[i] < finalPrimes[j] })
+
+	wgWorkers.Wait()
 	return finalPrimes
 }
 
+// CountPrimesInRange returns the number of primes in [low, high] without
+// allocating a slice of them, for ranges too large to materialize in memory.
+func CountPrimesInRange(low, high int64, numWorkers int) int64 {
+	if high < 2 || low > high {
+		return 0
+	}
+	if numWorkers <= 0 {
+		numWorkers = 1
+	}
+
+	basePrimes := rangeBasePrimes(high)
+	segments := planRangeSegments(low, high)
+
+	tasks := make(chan RangeSegmentTask, numWorkers)
+	results := make(chan RangeCountResult, numWorkers)
+
+	var wgWorkers sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wgWorkers.Add(1)
+		go rangeCountSegmentWorker(w, tasks, results, basePrimes, &wgWorkers)
+	}
+
+	go func() {
+		defer close(tasks)
+		for _, t := range segments {
+			tasks <- t
+		}
+	}()
+
+	var wgCollector sync.WaitGroup
+	wgCollector.Add(len(segments))
+	var total int64
+	go func() {
+		for result := range results {
+			total += result.count
+			wgCollector.Done()
+		}
+	}()
+	wgCollector.Wait()
+	close(results)
+
+	wgWorkers.Wait()
+	return total
+}
+
+// StreamPrimesInRange sieves [low, high] and sends every prime found, in
+// ascending order, to ch, closing ch once the range is exhausted. Each
+// segment is assigned to worker (index % numWorkers), and results are
+// received back in that same round-robin order, so the stream never has to
+// buffer more than one segment's worth of primes at a time.
+func StreamPrimesInRange(low, high int64, numWorkers int, ch chan<- int64) {
+	defer close(ch)
+	if high < 2 || low > high {
+		return
+	}
+	if numWorkers <= 0 {
+		numWorkers = 1
+	}
+
+	basePrimes := rangeBasePrimes(high)
+	segments := planRangeSegments(low, high)
+
+	workerTasks := make([]chan RangeSegmentTask, numWorkers)
+	workerResults := make([]chan RangeSegmentResult, numWorkers)
+	var wgWorkers sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		workerTasks[w] = make(chan RangeSegmentTask, 1)
+		workerResults[w] = make(chan RangeSegmentResult, 1)
+		wgWorkers.Add(1)
+		go rangeSegmentWorker(w, workerTasks[w], workerResults[w], basePrimes, &wgWorkers)
+	}
+
+	go func() {
+		for idx, t := range segments {
+			workerTasks[idx%numWorkers] <- t
+		}
+		for _, tc := range workerTasks {
+			close(tc)
+		}
+	}()
+
+	for idx := range segments {
+		result := <-workerResults[idx%numWorkers]
+		for _, p := range result.primes {
+			ch <- p
+		}
+	}
+	wgWorkers.Wait()
+}
+
+// --- Segment Analyzers (Twin / Cousin / Ormiston Pairs) ---
+
+// SegmentAnalyzer is notified of every pair of immediately consecutive
+// primes the sieve produces, in ascending order, including pairs that
+// straddle a segment boundary. It lets callers look for relationships
+// between neighboring primes without ever buffering the full prime list.
+type SegmentAnalyzer interface {
+	OnConsecutivePrimes(p, q int)
+}
+
+// TwinPrimeCollector gathers pairs (p, p+2).
+type TwinPrimeCollector struct {
+	Pairs [][2]int
+}
+
+func (c *TwinPrimeCollector) OnConsecutivePrimes(p, q int) {
+	if q-p == 2 {
+		c.Pairs = append(c.Pairs, [2]int{p, q})
+	}
+}
+
+// CousinPrimeCollector gathers consecutive primes (p, p+4). Since it only
+// sees consecutive pairs, (3, 7) is not gathered (5 lies between them).
+type CousinPrimeCollector struct {
+	Pairs [][2]int
+}
+
+func (c *CousinPrimeCollector) OnConsecutivePrimes(p, q int) {
+	if q-p == 4 {
+		c.Pairs = append(c.Pairs, [2]int{p, q})
+	}
+}
+
+// OrmistonPairCollector gathers pairs of consecutive primes whose decimal
+// digits are permutations of each other (e.g. 1913 and 1931).
+type OrmistonPairCollector struct {
+	Pairs [][2]int
+}
+
+func (c *OrmistonPairCollector) OnConsecutivePrimes(p, q int) {
+	if digitCounts(p) == digitCounts(q) {
+		c.Pairs = append(c.Pairs, [2]int{p, q})
+	}
+}
+
+// digitCounts returns how many times each decimal digit 0-9 occurs in n, so
+// two numbers are digit permutations of each other iff their counts match.
+func digitCounts(n int) [10]int {
+	var counts [10]int
+	if n == 0 {
+		counts[0] = 1
+		return counts
+	}
+	for n > 0 {
+		counts[n%10]++
+		n /= 10
+	}
+	return counts
+}
+
+// analyzeSegmentedSieve sieves [0, maxNum] the same way findPrimesWithSegmentedSieve
+// does, but instead of assembling the full prime list it feeds every pair of
+// consecutive primes to analyzers as each segment completes. Segments are
+// collected in dispatch order (one result channel per worker, consulted
+// round-robin) precisely so that the "previous prime" carried from one
+// segment to the next is correct across segment boundaries, without the
+// workers having to exchange boundary primes themselves. For a run to
+// N=200M this never holds the full prime list: at most a few segments' primes
+// are in memory at once (up to two buffered results per worker, plus the one
+// being analyzed).
+func analyzeSegmentedSieve(maxNum int, numWorkers int, analyzers ...SegmentAnalyzer) {
+	if maxNum < 2 || len(analyzers) == 0 {
+		return
+	}
+
+	sqrtMaxNum := int(math.Sqrt(float64(maxNum)))
+	basePrimes := sieveOfEratosthenesSequentialBase(sqrtMaxNum)
+
+	workerTasks := make([]chan SegmentTask, numWorkers)
+	workerResults := make([]chan SegmentResult, numWorkers)
+	var wgWorkers sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		workerTasks[w] = make(chan SegmentTask, 2)
+		workerResults[w] = make(chan SegmentResult, 2)
+		wgWorkers.Add(1)
+		go segmentedSieveWorker(w, workerTasks[w], workerResults[w], basePrimes, &wgWorkers)
+	}
+
+	segments := make([]SegmentTask, 0)
+	for low := 0; low <= maxNum; low += SegmentSizeInNumbers {
+		high := low + SegmentSizeInNumbers - 1
+		if high > maxNum {
+			high = maxNum
+		}
+		segments = append(segments, SegmentTask{low: low, high: high})
+	}
+
+	go func() {
+		for idx, t := range segments {
+			workerTasks[idx%numWorkers] <- t
+		}
+		for _, tc := range workerTasks {
+			close(tc)
+		}
+	}()
+
+	prevPrime := 0
+	havePrev := false
+	for idx := range segments {
+		result := <-workerResults[idx%numWorkers]
+		for _, p := range result.primes {
+			if havePrev {
+				for _, a := range analyzers {
+					a.OnConsecutivePrimes(prevPrime, p)
+				}
+			}
+			prevPrime = p
+			havePrev = true
+		}
+	}
+	wgWorkers.Wait()
+}
+
 func main() {
 	log.SetFlags(log.Ltime | log.Lmicroseconds)
 	log.Println("Concurrent Prime Finder (Segmented Sieve Version) - Starting")
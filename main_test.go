@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestSieveOfEratosthenesSequentialBase tests the optimized sequential sieve for base primes.
@@ -138,7 +139,331 @@ func TestFindPrimesWithSegmentedSieve_Orchestration(t *testing.T) {
 	}
 }
 
-// BenchmarkFindPrimesWithSegmentedSieve provides a basic benchmark.
+// TestComputeWheelResidues verifies the residue table backing the wheel sieve.
+func TestComputeWheelResidues(t *testing.T) {
+	residues := computeWheelResidues()
+	if len(residues) != 48 {
+		t.Fatalf("computeWheelResidues() returned %d residues; want 48", len(residues))
+	}
+	for _, r := range residues {
+		if r%2 == 0 || r%3 == 0 || r%5 == 0 || r%7 == 0 {
+			t.Errorf("residue %d is divisible by 2, 3, 5, or 7", r)
+		}
+	}
+	if residues[0] != 1 || residues[len(residues)-1] != 209 {
+		t.Errorf("residues = %v; want to start at 1 and end at 209", residues)
+	}
+}
+
+// TestWheelCoprimeCountBefore checks the prefix-count helper against a naive scan.
+func TestWheelCoprimeCountBefore(t *testing.T) {
+	naiveCount := func(n int) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			if i > 0 && gcd(i, WheelModulus) == 1 {
+				count++
+			}
+		}
+		return count
+	}
+
+	for _, n := range []int{0, 1, 11, 210, 211, 420, 1000} {
+		if got, want := wheelCoprimeCountBefore(n), naiveCount(n); got != want {
+			t.Errorf("wheelCoprimeCountBefore(%d) = %d; want %d", n, got, want)
+		}
+	}
+}
+
+// TestFindPrimesWithWheelSieve_Orchestration mirrors
+// TestFindPrimesWithSegmentedSieve_Orchestration but exercises the wheel sieve.
+func TestFindPrimesWithWheelSieve_Orchestration(t *testing.T) {
+	testCases := []struct {
+		name       string
+		maxNum     int
+		numWorkers int
+		expected   []int
+	}{
+		{"sieve up to 10, 1 worker", 10, 1, []int{2, 3, 5, 7}},
+		{"sieve up to 30, 4 workers", 30, 4, []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}},
+		{"sieve up to 1, 4 workers", 1, 4, []int{}},
+		{"sieve up to 100, 4 workers", 100, 4, []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73, 79, 83, 89, 97}},
+		{"sieve up to 500, 4 workers", 500, 4, sieveOfEratosthenesSequentialBase(500)},
+	}
+
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			primes := findPrimesWithWheelSieve(tc.maxNum, tc.numWorkers)
+			if !reflect.DeepEqual(primes, tc.expected) {
+				t.Errorf("findPrimesWithWheelSieve(%d, %d) = %v;\n want %v", tc.maxNum, tc.numWorkers, primes, tc.expected)
+			}
+		})
+	}
+}
+
+// TestPrimeStream_Next verifies in-order iteration over the first 20 primes.
+func TestPrimeStream_Next(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	ps := NewPrimeStream(2, 2)
+	defer ps.Close()
+
+	want := sieveOfEratosthenesSequentialBase(71) // the first 20 primes end at 71
+	got := make([]int, 0, 20)
+	for i := 0; i < 20; i++ {
+		p, ok := ps.Next()
+		if !ok {
+			t.Fatalf("Next() returned ok=false at i=%d", i)
+		}
+		got = append(got, p)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("first 20 primes = %v; want %v", got, want)
+	}
+}
+
+// TestPrimeStream_Skip verifies that Skip advances past the given count of primes.
+func TestPrimeStream_Skip(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	ps := NewPrimeStream(2, 2)
+	defer ps.Close()
+
+	ps.Skip(4) // skip 2, 3, 5, 7
+	p, _ := ps.Next()
+	if p != 11 {
+		t.Errorf("after Skip(4), Next() = %d; want 11", p)
+	}
+}
+
+// TestPrimeStream_Range verifies Range returns primes in [lo, hi] and that a
+// later call continues from where the previous one left off.
+func TestPrimeStream_Range(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	ps := NewPrimeStream(2, 2)
+	defer ps.Close()
+
+	got := ps.Range(10, 30)
+	want := []int{11, 13, 17, 19, 23, 29}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Range(10, 30) = %v; want %v", got, want)
+	}
+
+	next := ps.Range(31, 50)
+	wantNext := []int{31, 37, 41, 43, 47}
+	if !reflect.DeepEqual(next, wantNext) {
+		t.Errorf("Range(31, 50) after Range(10, 30) = %v; want %v", next, wantNext)
+	}
+}
+
+// TestPrimeStream_BaseGrowthAcrossSegments drives a PrimeStream past the
+// point where ensureBaseCoverage must grow the base-prime bound (baseBound^2
+// = 1,048,576 for the default initialStreamBaseBound) while pipelineDepth
+// keeps more than one segment dispatched-but-uncollected at once. Regression
+// test: ensureBaseCoverage used to restart the worker pool out from under
+// those in-flight segments, stranding their results in the old, discarded
+// result channels and deadlocking refill forever.
+func TestPrimeStream_BaseGrowthAcrossSegments(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	ps := NewPrimeStream(2, 4)
+	defer ps.Close()
+
+	const hi = 2_000_000 // past baseBound^2 (1,048,576) for the default initialStreamBaseBound
+	done := make(chan []int, 1)
+	go func() {
+		done <- ps.Range(0, hi)
+	}()
+
+	select {
+	case got := <-done:
+		want := findPrimesWithSegmentedSieve(hi, 2)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Range(0, %d) produced %d primes; want %d", hi, len(got), len(want))
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatal("Range(0, hi) did not return within 15s; ensureBaseCoverage likely deadlocked across a base-growth boundary")
+	}
+}
+
+// TestIsqrt64 checks that the integer square root helper returns floor(sqrt(n))
+// for a range of values, including some large enough to expose float64 rounding.
+func TestIsqrt64(t *testing.T) {
+	testCases := []int64{0, 1, 2, 3, 4, 15, 16, 17, 1_000_000, 1_000_000_000_000, 999_999_999_999_999_999}
+	for _, n := range testCases {
+		r := isqrt64(n)
+		if r*r > n || (r+1)*(r+1) <= n {
+			t.Errorf("isqrt64(%d) = %d; not floor(sqrt(%d))", n, r, n)
+		}
+	}
+}
+
+// TestFindPrimesInRange checks a range sieve that doesn't start at 0 against
+// the known primes in that range.
+func TestFindPrimesInRange(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	got := FindPrimesInRange(100, 150, 3)
+	want := []int64{101, 103, 107, 109, 113, 127, 131, 137, 139, 149}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindPrimesInRange(100, 150, 3) = %v; want %v", got, want)
+	}
+}
+
+// TestCountPrimesInRange checks the count-only variant against len(FindPrimesInRange(...)).
+func TestCountPrimesInRange(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	low, high := int64(1), int64(10_000)
+	want := int64(len(FindPrimesInRange(low, high, 4)))
+	got := CountPrimesInRange(low, high, 4)
+	if got != want {
+		t.Errorf("CountPrimesInRange(%d, %d, 4) = %d; want %d", low, high, got, want)
+	}
+}
+
+// TestStreamPrimesInRange checks that the streamed primes match
+// FindPrimesInRange for the same range, in the same order.
+func TestStreamPrimesInRange(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	low, high := int64(1), int64(10_000)
+	want := FindPrimesInRange(low, high, 4)
+
+	ch := make(chan int64, 16)
+	go StreamPrimesInRange(low, high, 4, ch)
+
+	got := make([]int64, 0, len(want))
+	for p := range ch {
+		got = append(got, p)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StreamPrimesInRange(%d, %d, 4) = %v;\n want %v", low, high, got, want)
+	}
+}
+
+// TestFillSegmentFromPreSieve checks the pre-sieve fill against a naive
+// per-number divisibility check, including a segment that wraps around
+// PreSievePrimesProduct.
+func TestFillSegmentFromPreSieve(t *testing.T) {
+	isDivisibleByPreSievePrime := func(n int) bool {
+		for _, p := range preSievePrimes {
+			if n%p == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	testCases := []struct {
+		low, numCount int
+	}{
+		{0, 100},
+		{20, 50},
+		{PreSievePrimesProduct - 10, 30}, // wraps around the pattern's period
+	}
+
+	for _, tc := range testCases {
+		bitset := make([]byte, (tc.numCount+7)/8)
+		fillSegmentFromPreSieve(bitset, tc.low, tc.numCount)
+		for i := 0; i < tc.numCount; i++ {
+			want := isDivisibleByPreSievePrime(tc.low + i)
+			got := isBitMarkedSegment(i, bitset)
+			if got != want {
+				t.Errorf("fillSegmentFromPreSieve low=%d: bit %d (n=%d) = %v; want %v", tc.low, i, tc.low+i, got, want)
+			}
+		}
+	}
+}
+
+// TestAnalyzeSegmentedSieve_TwinCousinOrmiston checks the three pair
+// collectors against hand-verified pairs within a small range, and cross-checks
+// the twin/cousin counts against a naive scan over findPrimesWithSegmentedSieve's output.
+func TestAnalyzeSegmentedSieve_TwinCousinOrmiston(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	const maxNum = 2000
+	twins := &TwinPrimeCollector{}
+	cousins := &CousinPrimeCollector{}
+	ormiston := &OrmistonPairCollector{}
+	analyzeSegmentedSieve(maxNum, 4, twins, cousins, ormiston)
+
+	primes := findPrimesWithSegmentedSieve(maxNum, 4)
+	var wantTwins, wantCousins [][2]int
+	for i := 1; i < len(primes); i++ {
+		p, q := primes[i-1], primes[i]
+		switch q - p {
+		case 2:
+			wantTwins = append(wantTwins, [2]int{p, q})
+		case 4:
+			wantCousins = append(wantCousins, [2]int{p, q})
+		}
+	}
+
+	if !reflect.DeepEqual(twins.Pairs, wantTwins) {
+		t.Errorf("TwinPrimeCollector.Pairs = %v;\n want %v", twins.Pairs, wantTwins)
+	}
+	if !reflect.DeepEqual(cousins.Pairs, wantCousins) {
+		t.Errorf("CousinPrimeCollector.Pairs = %v;\n want %v", cousins.Pairs, wantCousins)
+	}
+
+	foundKnownOrmiston := false
+	for _, pair := range ormiston.Pairs {
+		if pair == [2]int{1913, 1931} { // consecutive primes, both {1,1,3,9} digit multiset
+			foundKnownOrmiston = true
+		}
+		if digitCounts(pair[0]) != digitCounts(pair[1]) {
+			t.Errorf("OrmistonPairCollector pair %v is not a digit permutation", pair)
+		}
+	}
+	if !foundKnownOrmiston {
+		t.Errorf("OrmistonPairCollector.Pairs = %v; expected to include (1913, 1931)", ormiston.Pairs)
+	}
+}
+
+// TestEmitPrimes checks that EmitPrimes streams exactly the same primes, in
+// the same order, as findPrimesWithSegmentedSieve returns.
+func TestEmitPrimes(t *testing.T) {
+	originalLogOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(originalLogOutput)
+
+	const maxNum = 50_000
+	want := findPrimesWithSegmentedSieve(maxNum, 4)
+
+	ch := make(chan int, 16)
+	go EmitPrimes(maxNum, 4, ch)
+
+	got := make([]int, 0, len(want))
+	for p := range ch {
+		got = append(got, p)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EmitPrimes(%d, 4) produced %d primes; want %d (DeepEqual=%v)",
+			maxNum, len(got), len(want), reflect.DeepEqual(got, want))
+	}
+}
+
 func BenchmarkFindPrimesWithSegmentedSieve(b *testing.B) {
 	maxNum := 10_000_000                // A moderately large number for benchmark
 	numWorkers := NumSieveWorkersGlobal // Use the global config